@@ -1,15 +1,12 @@
 package main
 
 import (
-	"encoding/json"
+	"flag"
 	"fmt"
 	"net/http"
-	"net/url"
 	"os"
-	"regexp"
-	"sort"
-	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -21,175 +18,93 @@ var (
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "No artist provided")
-		os.Exit(1)
-	}
-
-	query := strings.Join(os.Args[1:], " ")
-	artist, err := findArtist(query)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
-		os.Exit(1)
-	}
-
-	albums, err := findAlbums(artist.Id)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
-		os.Exit(1)
-	}
-
-	if len(albums) == 0 {
-		fmt.Printf("%s has no albums yet\n", artist.Name)
-		return
-	}
-
-	fmt.Printf("Albums by %s\n", artist.Name)
-	for _, album := range albums {
-		fmt.Printf("%04d %s\n", album.Year, album.Title)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "discid":
+			runDiscID(os.Args[2:])
+			return
+		case "toc":
+			runTOC(os.Args[2:])
+			return
+		case "similar":
+			runSimilar(os.Args[2:])
+			return
+		}
 	}
-}
-
-type ArtistResult struct {
-	Artists []*Artist `json:"artists"`
-}
-
-type Artist struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
-}
-
-type ReleaseGroupResult struct {
-	ReleaseGroups []*ReleaseGroup `json:"release-groups"`
-}
-
-type ReleaseGroup struct {
-	Id               string   `json:"id"`
-	Title            string   `json:"title"`
-	PrimaryType      string   `json:"primary-type"`
-	SecondaryTypes   []string `json:"secondary-types"`
-	FirstReleaseDate string   `json:"first-release-date"`
-}
 
-type Album struct {
-	Title string
-	Year  int
+	runLookup(os.Args[1:])
 }
 
-type AlbumByYear []*Album
-
-func (a AlbumByYear) Len() int           { return len(a) }
-func (a AlbumByYear) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a AlbumByYear) Less(i, j int) bool { return a[i].Year < a[j].Year }
-
-func findArtist(name string) (*Artist, error) {
-	query := url.Values{}
-	query.Add("query", fmt.Sprintf("artist:%s", name))
-	query.Add("limit", "1")
-	query.Add("fmt", "json")
-
-	req := &http.Request{
-		Method: "GET",
-		Host:   "musicbrainz.org",
-		URL: &url.URL{
-			Host:     "musicbrainz.org",
-			Scheme:   "http",
-			Path:     "/ws/2/artist/",
-			RawQuery: query.Encode(),
-		},
-		Header: http.Header{
-			"User-Agent": {UserAgent},
-		},
+// runLookup implements the default `albums <artist>` behaviour: resolve the
+// artist and print their discography.
+func runLookup(args []string) {
+	fs := flag.NewFlagSet("albums", flag.ExitOnError)
+	sourceName := fs.String("source", "", "Metadata source to use (musicbrainz, discogs, vgmdb). Defaults to trying all sources and merging the results.")
+	detail := fs.Bool("detail", false, "Fetch per-release detail: genres, label, catalog number, cover art and track listing")
+	format := fs.String("format", "table", "Output format: table, json or tsv")
+	include := fs.String("include", "", "Comma separated release types to include (album,ep,single,live,compilation,soundtrack,remix,...). Defaults to album only.")
+	exclude := fs.String("exclude", "", "Comma separated release types to exclude")
+	fromYear := fs.Int("from-year", 0, "Only show albums released in or after this year")
+	toYear := fs.Int("to-year", 0, "Only show albums released in or before this year")
+	sortBy := fs.String("sort", "year", "Sort albums by: year, title or type")
+	noCache, cacheTTL := addCacheFlags(fs)
+	fs.Parse(args)
+
+	http.DefaultClient.Transport = NewCachingClient(!*noCache, *cacheTTL)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintf(os.Stderr, "No artist provided")
+		os.Exit(1)
 	}
 
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	query := strings.Join(fs.Args(), " ")
 
-	artistResult := &ArtistResult{}
-	err = json.NewDecoder(res.Body).Decode(&artistResult)
-	if err != nil {
-		return nil, err
-	}
+	var artist *Artist
+	var albums []*Album
+	var err error
 
-	if len(artistResult.Artists) == 0 {
-		return nil, fmt.Errorf("No artists found")
+	if *sourceName != "" {
+		source, sourceErr := sourceByName(*sourceName)
+		if sourceErr != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", sourceErr.Error())
+			os.Exit(1)
+		}
+		artist, albums, err = lookupAlbums(source, query)
+	} else {
+		artist, albums, err = lookupAlbumsMerged(query)
 	}
 
-	return artistResult.Artists[0], nil
-}
-
-func findAlbums(artistId string) ([]*Album, error) {
-	releaseGroups, err := findReleaseGroups(artistId)
 	if err != nil {
-		return nil, err
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		os.Exit(1)
 	}
 
-	albums := make([]*Album, 0, 0)
+	albums = filterAlbums(albums, newAlbumFilter(*include, *exclude, *fromYear, *toYear))
+	sortAlbums(albums, *sortBy)
 
-	for _, rg := range releaseGroups {
-		// Skip non-pure albums
-		if len(rg.SecondaryTypes) > 0 {
-			continue
+	if *detail {
+		if err := fetchAlbumDetails(albums); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+			os.Exit(1)
 		}
-
-		albums = append(albums, &Album{
-			Title: rg.Title,
-			Year:  formatYear(rg.FirstReleaseDate),
-		})
-	}
-
-	// Sort albums by year
-	sort.Sort(AlbumByYear(albums))
-
-	return albums, nil
-}
-
-func findReleaseGroups(artistId string) ([]*ReleaseGroup, error) {
-	query := url.Values{}
-	query.Add("artist", artistId)
-	query.Add("type", "album")
-	query.Add("limit", "100")
-	query.Add("fmt", "json")
-
-	req := &http.Request{
-		Method: "GET",
-		Host:   "musicbrainz.org",
-		URL: &url.URL{
-			Host:     "musicbrainz.org",
-			Scheme:   "http",
-			Path:     "/ws/2/release-group/",
-			RawQuery: query.Encode(),
-		},
-		Header: http.Header{
-			"User-Agent": {UserAgent},
-		},
-	}
-
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
 	}
-	defer res.Body.Close()
 
-	releaseGroupResult := &ReleaseGroupResult{}
-	err = json.NewDecoder(res.Body).Decode(&releaseGroupResult)
-	if err != nil {
-		return nil, err
+	if err := printAlbums(os.Stdout, artist, albums, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		os.Exit(1)
 	}
-
-	return releaseGroupResult.ReleaseGroups, nil
 }
 
-func formatYear(date string) int {
-	re := regexp.MustCompile("^([0-9]{4})")
-	matches := re.FindStringSubmatch(date)
-	if len(matches) != 2 {
-		return 0
-	}
+// addCacheFlags registers the --no-cache and --cache-ttl flags shared by
+// every subcommand that talks to a MetadataSource.
+func addCacheFlags(fs *flag.FlagSet) (*bool, *time.Duration) {
+	noCache := fs.Bool("no-cache", false, "Disable the on-disk response cache")
+	cacheTTL := fs.Duration("cache-ttl", 0, "Override the cache TTL for all responses (e.g. 1h, 24h). Defaults to 1 day for searches and 14 days for release-groups.")
+	return noCache, cacheTTL
+}
 
-	year, _ := strconv.Atoi(matches[1])
-	return year
+// normalizeTitle makes two album titles from different sources comparable,
+// e.g. "Abbey Road" vs "abbey road".
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
 }
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSearchTTL       = 24 * time.Hour
+	defaultReleaseGroupTTL = 14 * 24 * time.Hour
+
+	musicBrainzRateLimit = time.Second
+)
+
+// CachingClient is an http.RoundTripper that sits in front of the real
+// transport, rate-limiting requests to musicbrainz.org to 1/sec as their
+// ToS requires, and caching GET responses on disk so repeated lookups
+// don't hit the network at all.
+type CachingClient struct {
+	Transport   http.RoundTripper
+	CacheDir    string
+	Enabled     bool
+	TTLOverride time.Duration
+
+	mu       sync.Mutex
+	lastHost map[string]time.Time
+}
+
+// NewCachingClient builds a CachingClient using the default
+// $XDG_CACHE_HOME/albums cache directory. If ttlOverride is non-zero it is
+// used for every response instead of the per-endpoint defaults.
+func NewCachingClient(enabled bool, ttlOverride time.Duration) *CachingClient {
+	return &CachingClient{
+		Transport:   http.DefaultTransport,
+		CacheDir:    defaultCacheDir(),
+		Enabled:     enabled,
+		TTLOverride: ttlOverride,
+		lastHost:    make(map[string]time.Time),
+	}
+}
+
+func (c *CachingClient) RoundTrip(req *http.Request) (*http.Response, error) {
+	cacheable := c.Enabled && req.Method == "GET"
+
+	if cacheable {
+		if body, ok := c.readCache(req); ok {
+			return newCachedResponse(req, body), nil
+		}
+	}
+
+	// Only throttle when we're actually about to hit the network; a cache
+	// hit above already returned.
+	c.throttle(req)
+
+	res, err := c.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheable && res.StatusCode == http.StatusOK {
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		c.writeCache(req, body)
+		res.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return res, nil
+}
+
+// throttle blocks until it is safe to send another request to req's host,
+// currently only enforced for musicbrainz.org.
+func (c *CachingClient) throttle(req *http.Request) {
+	if req.URL.Host != "musicbrainz.org" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if last, ok := c.lastHost[req.URL.Host]; ok {
+		if wait := musicBrainzRateLimit - time.Since(last); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+	c.lastHost[req.URL.Host] = time.Now()
+}
+
+func (c *CachingClient) readCache(req *http.Request) ([]byte, bool) {
+	path := c.cachePath(req)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false
+	}
+
+	if time.Since(info.ModTime()) > c.ttlFor(req) {
+		return nil, false
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	return body, true
+}
+
+func (c *CachingClient) writeCache(req *http.Request, body []byte) {
+	path := c.cachePath(req)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+
+	// Best-effort: a failed cache write shouldn't fail the lookup.
+	_ = os.WriteFile(path, body, 0644)
+}
+
+func (c *CachingClient) cachePath(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String()))
+	return filepath.Join(c.CacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *CachingClient) ttlFor(req *http.Request) time.Duration {
+	if c.TTLOverride > 0 {
+		return c.TTLOverride
+	}
+
+	if strings.Contains(req.URL.Path, "release-group") {
+		return defaultReleaseGroupTTL
+	}
+
+	return defaultSearchTTL
+}
+
+func defaultCacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "albums")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "albums")
+	}
+
+	return filepath.Join(home, ".cache", "albums")
+}
+
+func newCachedResponse(req *http.Request, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Status:        strconv.Itoa(http.StatusOK) + " OK",
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingTransport is a stub http.RoundTripper that always succeeds and
+// counts how many times it was actually invoked, so tests can tell a cache
+// hit from a real network call.
+type countingTransport struct {
+	calls int
+	body  string
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(t.body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func TestCachingClientTTLFor(t *testing.T) {
+	c := &CachingClient{}
+
+	searchReq := &http.Request{URL: &url.URL{Path: "/ws/2/artist/"}}
+	if got := c.ttlFor(searchReq); got != defaultSearchTTL {
+		t.Fatalf("expected search TTL %v, got %v", defaultSearchTTL, got)
+	}
+
+	releaseGroupReq := &http.Request{URL: &url.URL{Path: "/ws/2/release-group/"}}
+	if got := c.ttlFor(releaseGroupReq); got != defaultReleaseGroupTTL {
+		t.Fatalf("expected release-group TTL %v, got %v", defaultReleaseGroupTTL, got)
+	}
+
+	c.TTLOverride = time.Hour
+	if got := c.ttlFor(releaseGroupReq); got != time.Hour {
+		t.Fatalf("expected override TTL to win, got %v", got)
+	}
+}
+
+func TestCachingClientCachePathStable(t *testing.T) {
+	c := &CachingClient{CacheDir: "/tmp/albums-cache-test"}
+
+	req := &http.Request{URL: &url.URL{Scheme: "http", Host: "musicbrainz.org", Path: "/ws/2/artist/", RawQuery: "query=test"}}
+
+	if c.cachePath(req) != c.cachePath(req) {
+		t.Fatal("cachePath should be deterministic for the same request")
+	}
+
+	other := &http.Request{URL: &url.URL{Scheme: "http", Host: "musicbrainz.org", Path: "/ws/2/artist/", RawQuery: "query=other"}}
+	if c.cachePath(req) == c.cachePath(other) {
+		t.Fatal("cachePath should differ for different URLs")
+	}
+}
+
+func TestCachingClientRoundTripCachesResponses(t *testing.T) {
+	transport := &countingTransport{body: `{"ok":true}`}
+	c := &CachingClient{
+		Transport: transport,
+		CacheDir:  t.TempDir(),
+		Enabled:   true,
+		lastHost:  make(map[string]time.Time),
+	}
+
+	req := &http.Request{Method: "GET", URL: &url.URL{Scheme: "http", Host: "example.org", Path: "/x"}}
+
+	if _, err := c.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if transport.calls != 1 {
+		t.Fatalf("expected a single real request, got %d", transport.calls)
+	}
+}
+
+func TestCachingClientRoundTripSkipsThrottleOnCacheHit(t *testing.T) {
+	transport := &countingTransport{body: `{"ok":true}`}
+	c := &CachingClient{
+		Transport: transport,
+		CacheDir:  t.TempDir(),
+		Enabled:   true,
+		lastHost:  make(map[string]time.Time),
+	}
+
+	req := &http.Request{Method: "GET", URL: &url.URL{Scheme: "http", Host: "musicbrainz.org", Path: "/ws/2/artist/"}}
+
+	if _, err := c.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Pretend a request to this host just happened, so a naive
+	// throttle-before-cache-check would sleep up to musicBrainzRateLimit.
+	c.lastHost["musicbrainz.org"] = time.Now()
+
+	start := time.Now()
+	if _, err := c.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	elapsed := time.Since(start)
+
+	if transport.calls != 1 {
+		t.Fatalf("expected cache hit to avoid a second real request, got %d calls", transport.calls)
+	}
+	if elapsed >= musicBrainzRateLimit {
+		t.Fatalf("cache hit should not be rate-limited, took %v", elapsed)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// pseudoDiscID is the special MusicBrainz disc ID used to look up a release
+// purely by TOC, without an actual DiscID hash.
+// See https://musicbrainz.org/doc/Disc_ID_Calculation.
+const pseudoDiscID = "-"
+
+type mbDiscResult struct {
+	Id       string           `json:"id"`
+	Releases []*mbDiscRelease `json:"releases"`
+}
+
+type mbDiscRelease struct {
+	Title        string              `json:"title"`
+	Date         string              `json:"date"`
+	ArtistCredit []*mbArtistCredit   `json:"artist-credit"`
+	Media        []*mbDiscReleaseMed `json:"media"`
+}
+
+type mbArtistCredit struct {
+	Name string `json:"name"`
+}
+
+type mbDiscReleaseMed struct {
+	Tracks []*mbDiscTrack `json:"tracks"`
+}
+
+type mbDiscTrack struct {
+	Position int    `json:"position"`
+	Title    string `json:"title"`
+	Length   int    `json:"length"`
+}
+
+// runDiscID implements `albums discid <discid>`.
+func runDiscID(args []string) {
+	fs := flag.NewFlagSet("discid", flag.ExitOnError)
+	noCache, cacheTTL := addCacheFlags(fs)
+	fs.Parse(args)
+
+	http.DefaultClient.Transport = NewCachingClient(!*noCache, *cacheTTL)
+
+	if fs.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: albums discid <discid>\n")
+		os.Exit(1)
+	}
+
+	lookupDisc(fs.Arg(0), "")
+}
+
+// runTOC implements `albums toc <toc-string>`, where <toc-string> mirrors
+// MusicBrainz's "first-track last-track leadout offsets..." format.
+func runTOC(args []string) {
+	fs := flag.NewFlagSet("toc", flag.ExitOnError)
+	noCache, cacheTTL := addCacheFlags(fs)
+	fs.Parse(args)
+
+	http.DefaultClient.Transport = NewCachingClient(!*noCache, *cacheTTL)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintf(os.Stderr, "Usage: albums toc <first-track> <last-track> <leadout> <offsets...>\n")
+		os.Exit(1)
+	}
+
+	lookupDisc(pseudoDiscID, strings.Join(fs.Args(), " "))
+}
+
+func lookupDisc(discID string, toc string) {
+	result, err := fetchDisc(discID, toc)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	printDisc(os.Stdout, result)
+}
+
+func printDisc(w io.Writer, result *mbDiscResult) {
+	if len(result.Releases) == 0 {
+		fmt.Fprintln(w, "No matching releases found")
+		return
+	}
+
+	for _, release := range result.Releases {
+		fmt.Fprintf(w, "%04d %s - %s\n", formatYear(release.Date), artistCreditNames(release.ArtistCredit), release.Title)
+
+		for _, medium := range release.Media {
+			for _, track := range medium.Tracks {
+				fmt.Fprintf(w, "  %02d %s\n", track.Position, track.Title)
+			}
+		}
+	}
+}
+
+func artistCreditNames(credits []*mbArtistCredit) string {
+	names := make([]string, 0, len(credits))
+	for _, credit := range credits {
+		names = append(names, credit.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func fetchDisc(discID string, toc string) (*mbDiscResult, error) {
+	params := url.Values{}
+	params.Add("inc", "artist-credits+recordings")
+	params.Add("fmt", "json")
+	if toc != "" {
+		params.Add("toc", toc)
+	}
+
+	req := &http.Request{
+		Method: "GET",
+		Host:   "musicbrainz.org",
+		URL: &url.URL{
+			Host:     "musicbrainz.org",
+			Scheme:   "http",
+			Path:     fmt.Sprintf("/ws/2/discid/%s", discID),
+			RawQuery: params.Encode(),
+		},
+		Header: http.Header{
+			"User-Agent": {UserAgent},
+		},
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	result := &mbDiscResult{}
+	err = json.NewDecoder(res.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestArtistCreditNames(t *testing.T) {
+	credits := []*mbArtistCredit{{Name: "Boards"}, {Name: "of Canada"}}
+	if got := artistCreditNames(credits); got != "Boards, of Canada" {
+		t.Fatalf("expected joined artist credit names, got %q", got)
+	}
+
+	if got := artistCreditNames(nil); got != "" {
+		t.Fatalf("expected empty string for no credits, got %q", got)
+	}
+}
+
+func TestPrintDiscNoReleases(t *testing.T) {
+	var buf bytes.Buffer
+	printDisc(&buf, &mbDiscResult{})
+
+	if got := buf.String(); got != "No matching releases found\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestPrintDiscRelease(t *testing.T) {
+	var buf bytes.Buffer
+	printDisc(&buf, &mbDiscResult{
+		Releases: []*mbDiscRelease{
+			{
+				Title:        "Geogaddi",
+				Date:         "2002-02-18",
+				ArtistCredit: []*mbArtistCredit{{Name: "Boards of Canada"}},
+				Media: []*mbDiscReleaseMed{
+					{Tracks: []*mbDiscTrack{{Position: 1, Title: "Ready Lets Go"}}},
+				},
+			},
+		},
+	})
+
+	got := buf.String()
+	if !strings.Contains(got, "2002 Boards of Canada - Geogaddi") {
+		t.Fatalf("expected release header in output, got %q", got)
+	}
+	if !strings.Contains(got, "01 Ready Lets Go") {
+		t.Fatalf("expected track listing in output, got %q", got)
+	}
+}
+
+// fetchDiscTransport is a fake http.RoundTripper that inspects the request
+// built by fetchDisc and returns a canned MusicBrainz discid response.
+type fetchDiscTransport struct {
+	gotPath  string
+	gotQuery url.Values
+}
+
+func (t *fetchDiscTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.gotPath = req.URL.Path
+	t.gotQuery = req.URL.Query()
+
+	body, _ := json.Marshal(&mbDiscResult{Id: "disc-1"})
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func TestFetchDiscByDiscID(t *testing.T) {
+	transport := &fetchDiscTransport{}
+	restore := swapDefaultTransport(transport)
+	defer restore()
+
+	result, err := fetchDisc("abc123", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result.Id != "disc-1" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if transport.gotPath != "/ws/2/discid/abc123" {
+		t.Fatalf("unexpected path: %s", transport.gotPath)
+	}
+	if transport.gotQuery.Get("toc") != "" {
+		t.Fatalf("expected no toc param for a plain discid lookup, got %q", transport.gotQuery.Get("toc"))
+	}
+}
+
+func TestFetchDiscByTOC(t *testing.T) {
+	transport := &fetchDiscTransport{}
+	restore := swapDefaultTransport(transport)
+	defer restore()
+
+	toc := "1 10 150 150 13000"
+	_, err := fetchDisc(pseudoDiscID, toc)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if transport.gotPath != "/ws/2/discid/-" {
+		t.Fatalf("unexpected path: %s", transport.gotPath)
+	}
+	if transport.gotQuery.Get("toc") != toc {
+		t.Fatalf("expected toc param %q, got %q", toc, transport.gotQuery.Get("toc"))
+	}
+}
+
+// swapDefaultTransport installs t as http.DefaultClient's Transport and
+// returns a func that restores the previous one.
+func swapDefaultTransport(t http.RoundTripper) func() {
+	prev := http.DefaultClient.Transport
+	http.DefaultClient.Transport = t
+	return func() { http.DefaultClient.Transport = prev }
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DiscogsSource looks up artists and releases via the Discogs API
+// (https://www.discogs.com/developers/), which is useful for pressing
+// and label information that MusicBrainz often lacks.
+type DiscogsSource struct{}
+
+func (s *DiscogsSource) Name() string {
+	return "discogs"
+}
+
+type discogsSearchResult struct {
+	Results []*discogsSearchEntry `json:"results"`
+}
+
+type discogsSearchEntry struct {
+	Id    int    `json:"id"`
+	Title string `json:"title"`
+	Type  string `json:"type"`
+}
+
+type discogsReleaseResult struct {
+	Releases []*discogsRelease `json:"releases"`
+}
+
+type discogsRelease struct {
+	Id    int    `json:"id"`
+	Title string `json:"title"`
+	Year  int    `json:"year"`
+	Type  string `json:"type"`
+	Role  string `json:"role"`
+}
+
+type discogsReleaseDetail struct {
+	Genres    []string             `json:"genres"`
+	Styles    []string             `json:"styles"`
+	Labels    []*discogsLabel      `json:"labels"`
+	Tracklist []*discogsTrack      `json:"tracklist"`
+	Images    []*discogsReleaseImg `json:"images"`
+}
+
+type discogsLabel struct {
+	Name          string `json:"name"`
+	CatalogNumber string `json:"catno"`
+}
+
+type discogsTrack struct {
+	Position string `json:"position"`
+	Title    string `json:"title"`
+	Duration string `json:"duration"`
+}
+
+type discogsReleaseImg struct {
+	Uri string `json:"uri"`
+}
+
+func (s *DiscogsSource) FindArtist(query string) (*Artist, error) {
+	params := url.Values{}
+	params.Add("q", query)
+	params.Add("type", "artist")
+
+	result := &discogsSearchResult{}
+	err := s.get("/database/search", params, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Results) == 0 {
+		return nil, fmt.Errorf("No artists found")
+	}
+
+	artist := result.Results[0]
+	return &Artist{Id: strconv.Itoa(artist.Id), Name: artist.Title}, nil
+}
+
+func (s *DiscogsSource) FindAlbums(artistID string) ([]*Album, error) {
+	result := &discogsReleaseResult{}
+	err := s.get(fmt.Sprintf("/artists/%s/releases", artistID), url.Values{}, result)
+	if err != nil {
+		return nil, err
+	}
+
+	albums := make([]*Album, 0, 0)
+
+	for _, release := range result.Releases {
+		// Only keep the artist's own releases, not guest appearances
+		if release.Role != "Main" {
+			continue
+		}
+
+		albums = append(albums, &Album{
+			Id:    strconv.Itoa(release.Id),
+			Title: release.Title,
+			Year:  release.Year,
+			// Discogs' artist-releases endpoint doesn't expose MB-style
+			// secondary types, so everything is treated as a plain album.
+			Type:       "album",
+			sourceName: s.Name(),
+		})
+	}
+
+	return albums, nil
+}
+
+// FindAlbumDetail fetches genres/styles, label+catalog number, the track
+// listing and a cover image URL for a single Discogs release.
+func (s *DiscogsSource) FindAlbumDetail(album *Album) error {
+	if album.Id == "" {
+		return nil
+	}
+
+	detail := &discogsReleaseDetail{}
+	err := s.get(fmt.Sprintf("/releases/%s", album.Id), url.Values{}, detail)
+	if err != nil {
+		return err
+	}
+
+	album.Genres = append(append([]string{}, detail.Genres...), detail.Styles...)
+	if len(album.Genres) > 0 {
+		album.Genre = album.Genres[0]
+	}
+
+	if len(detail.Labels) > 0 {
+		album.Label = detail.Labels[0].Name
+		album.CatalogNumber = detail.Labels[0].CatalogNumber
+	}
+
+	if len(detail.Images) > 0 {
+		album.CoverArtURL = detail.Images[0].Uri
+	}
+
+	for i, track := range detail.Tracklist {
+		album.Tracks = append(album.Tracks, &Track{
+			Position:        i + 1,
+			Title:           track.Title,
+			DurationSeconds: parseDiscogsDuration(track.Duration),
+		})
+	}
+
+	return nil
+}
+
+// parseDiscogsDuration parses Discogs' "m:ss" track duration format.
+func parseDiscogsDuration(duration string) int {
+	parts := strings.SplitN(duration, ":", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+
+	minutes, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0
+	}
+
+	return minutes*60 + seconds
+}
+
+func (s *DiscogsSource) get(path string, params url.Values, out interface{}) error {
+	if token := os.Getenv("DISCOGS_TOKEN"); token != "" {
+		params.Add("token", token)
+	}
+
+	req := &http.Request{
+		Method: "GET",
+		Host:   "api.discogs.com",
+		URL: &url.URL{
+			Host:     "api.discogs.com",
+			Scheme:   "https",
+			Path:     path,
+			RawQuery: params.Encode(),
+		},
+		Header: http.Header{
+			"User-Agent": {UserAgent},
+		},
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("Discogs request failed: %s", res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
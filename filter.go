@@ -0,0 +1,141 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// defaultIncludeTypes preserves the tool's original behaviour: when
+// neither --include nor --exclude is given, only pure studio albums are
+// shown.
+var defaultIncludeTypes = []string{"album"}
+
+// AlbumFilter selects albums by release type and year range.
+type AlbumFilter struct {
+	Include  map[string]bool
+	Exclude  map[string]bool
+	FromYear int
+	ToYear   int
+
+	// PureOnly reproduces the tool's original behaviour of dropping any
+	// release-group with secondary types at all, regardless of Include.
+	// Only set when neither --include nor --exclude was given.
+	PureOnly bool
+}
+
+// newAlbumFilter builds an AlbumFilter from the --include/--exclude/
+// --from-year/--to-year flag values.
+func newAlbumFilter(include, exclude string, fromYear, toYear int) *AlbumFilter {
+	defaulted := include == "" && exclude == ""
+
+	includeTypes := splitTypes(include)
+	if defaulted {
+		includeTypes = defaultIncludeTypes
+	}
+
+	return &AlbumFilter{
+		Include:  toTypeSet(includeTypes),
+		Exclude:  toTypeSet(splitTypes(exclude)),
+		FromYear: fromYear,
+		ToYear:   toYear,
+		PureOnly: defaulted,
+	}
+}
+
+func splitTypes(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	parts := strings.Split(value, ",")
+	types := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part != "" {
+			types = append(types, part)
+		}
+	}
+	return types
+}
+
+func toTypeSet(types []string) map[string]bool {
+	set := make(map[string]bool, len(types))
+	for _, t := range types {
+		set[t] = true
+	}
+	return set
+}
+
+// Matches reports whether album passes the filter.
+func (f *AlbumFilter) Matches(album *Album) bool {
+	if f.FromYear > 0 && album.Year < f.FromYear {
+		return false
+	}
+	if f.ToYear > 0 && album.Year > f.ToYear {
+		return false
+	}
+
+	if f.PureOnly && len(album.SecondaryTypes) > 0 {
+		return false
+	}
+
+	types := albumTypes(album)
+
+	if len(f.Include) > 0 && !anyTypeIn(types, f.Include) {
+		return false
+	}
+	if len(f.Exclude) > 0 && anyTypeIn(types, f.Exclude) {
+		return false
+	}
+
+	return true
+}
+
+func albumTypes(album *Album) []string {
+	types := make([]string, 0, len(album.SecondaryTypes)+1)
+	if album.Type != "" {
+		types = append(types, album.Type)
+	}
+	types = append(types, album.SecondaryTypes...)
+	return types
+}
+
+func anyTypeIn(types []string, set map[string]bool) bool {
+	for _, t := range types {
+		if set[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// filterAlbums returns the albums that pass f, preserving order.
+func filterAlbums(albums []*Album, f *AlbumFilter) []*Album {
+	filtered := make([]*Album, 0, len(albums))
+	for _, album := range albums {
+		if f.Matches(album) {
+			filtered = append(filtered, album)
+		}
+	}
+	return filtered
+}
+
+// sortAlbums sorts albums in place by the given key ("year", "title" or
+// "type"). Unknown keys fall back to sorting by year.
+func sortAlbums(albums []*Album, by string) {
+	switch by {
+	case "title":
+		sort.Slice(albums, func(i, j int) bool {
+			return normalizeTitle(albums[i].Title) < normalizeTitle(albums[j].Title)
+		})
+	case "type":
+		sort.Slice(albums, func(i, j int) bool {
+			if albums[i].Type != albums[j].Type {
+				return albums[i].Type < albums[j].Type
+			}
+			return albums[i].Year < albums[j].Year
+		})
+	default:
+		sort.Sort(AlbumByYear(albums))
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import "testing"
+
+func TestAlbumFilterDefaultExcludesSecondaryTypes(t *testing.T) {
+	filter := newAlbumFilter("", "", 0, 0)
+
+	album := &Album{Type: "album", SecondaryTypes: []string{"live"}, Year: 2000}
+	if filter.Matches(album) {
+		t.Fatal("default filter should drop a release-group with any secondary type")
+	}
+
+	pure := &Album{Type: "album", Year: 2000}
+	if !filter.Matches(pure) {
+		t.Fatal("default filter should keep a pure album")
+	}
+}
+
+func TestAlbumFilterInclude(t *testing.T) {
+	filter := newAlbumFilter("live,soundtrack", "", 0, 0)
+
+	live := &Album{Type: "album", SecondaryTypes: []string{"live"}}
+	if !filter.Matches(live) {
+		t.Fatal("--include=live should match an album with secondary type live")
+	}
+
+	studio := &Album{Type: "album"}
+	if filter.Matches(studio) {
+		t.Fatal("--include=live,soundtrack should not match a plain studio album")
+	}
+}
+
+func TestAlbumFilterExclude(t *testing.T) {
+	filter := newAlbumFilter("", "live", 0, 0)
+
+	live := &Album{Type: "album", SecondaryTypes: []string{"live"}}
+	if filter.Matches(live) {
+		t.Fatal("--exclude=live should drop a live album")
+	}
+
+	studio := &Album{Type: "album"}
+	if !filter.Matches(studio) {
+		t.Fatal("--exclude=live should keep everything else")
+	}
+}
+
+func TestAlbumFilterYearRange(t *testing.T) {
+	filter := newAlbumFilter("album", "", 1990, 2000)
+
+	if filter.Matches(&Album{Type: "album", Year: 1989}) {
+		t.Fatal("album released before from-year should be dropped")
+	}
+	if filter.Matches(&Album{Type: "album", Year: 2001}) {
+		t.Fatal("album released after to-year should be dropped")
+	}
+	if !filter.Matches(&Album{Type: "album", Year: 1995}) {
+		t.Fatal("album released within range should be kept")
+	}
+}
+
+func TestSortAlbums(t *testing.T) {
+	albums := []*Album{
+		{Title: "B", Year: 2001, Type: "ep"},
+		{Title: "A", Year: 1999, Type: "album"},
+		{Title: "C", Year: 1995, Type: "album"},
+	}
+
+	sortAlbums(albums, "year")
+	if albums[0].Year != 1995 || albums[2].Year != 2001 {
+		t.Fatalf("expected albums sorted by year, got %+v", albums)
+	}
+
+	sortAlbums(albums, "title")
+	if albums[0].Title != "A" || albums[2].Title != "C" {
+		t.Fatalf("expected albums sorted by title, got %+v", albums)
+	}
+
+	sortAlbums(albums, "type")
+	if albums[len(albums)-1].Type != "ep" {
+		t.Fatalf("expected ep sorted after album, got %+v", albums)
+	}
+}
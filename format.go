@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// albumListing is the JSON/table/tsv wire format for an artist's
+// discography.
+type albumListing struct {
+	Artist *Artist  `json:"artist"`
+	Albums []*Album `json:"albums"`
+}
+
+// printAlbums renders albums in the requested format ("table", "json" or
+// "tsv") to w.
+func printAlbums(w io.Writer, artist *Artist, albums []*Album, format string) error {
+	switch format {
+	case "", "table":
+		printAlbumsTable(w, artist, albums)
+		return nil
+	case "tsv":
+		printAlbumsTSV(w, albums)
+		return nil
+	case "json":
+		return json.NewEncoder(w).Encode(&albumListing{Artist: artist, Albums: albums})
+	default:
+		return fmt.Errorf("Unknown format: %s", format)
+	}
+}
+
+func printAlbumsTable(w io.Writer, artist *Artist, albums []*Album) {
+	if len(albums) == 0 {
+		fmt.Fprintf(w, "%s has no albums yet\n", artist.Name)
+		return
+	}
+
+	fmt.Fprintf(w, "Albums by %s\n", artist.Name)
+	for _, album := range albums {
+		fmt.Fprintf(w, "%04d %s%s\n", album.Year, album.Title, albumDetailSuffix(album))
+
+		for _, track := range album.Tracks {
+			fmt.Fprintf(w, "  %02d %s%s\n", track.Position, track.Title, trackDurationSuffix(track))
+		}
+	}
+}
+
+func printAlbumsTSV(w io.Writer, albums []*Album) {
+	for _, album := range albums {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+			album.Year,
+			album.Title,
+			strings.Join(album.Genres, ","),
+			album.Label,
+			album.CatalogNumber,
+			album.CoverArtURL,
+		)
+	}
+}
+
+func albumDetailSuffix(album *Album) string {
+	var parts []string
+
+	if len(album.Genres) > 0 {
+		parts = append(parts, strings.Join(album.Genres, ", "))
+	}
+	if album.Label != "" {
+		label := album.Label
+		if album.CatalogNumber != "" {
+			label = fmt.Sprintf("%s %s", label, album.CatalogNumber)
+		}
+		parts = append(parts, label)
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [%s]", strings.Join(parts, " / "))
+}
+
+func trackDurationSuffix(track *Track) string {
+	if track.DurationSeconds == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d:%02d)", track.DurationSeconds/60, track.DurationSeconds%60)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintAlbumsTableEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	printAlbumsTable(&buf, &Artist{Name: "Boards of Canada"}, nil)
+
+	if got := buf.String(); got != "Boards of Canada has no albums yet\n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestPrintAlbumsTableWithDetail(t *testing.T) {
+	var buf bytes.Buffer
+	album := &Album{
+		Title:         "Geogaddi",
+		Year:          2002,
+		Genres:        []string{"IDM", "Ambient"},
+		Label:         "Warp",
+		CatalogNumber: "WARPCD109",
+		Tracks:        []*Track{{Position: 1, Title: "Ready Lets Go", DurationSeconds: 95}},
+	}
+
+	printAlbumsTable(&buf, &Artist{Name: "Boards of Canada"}, []*Album{album})
+
+	got := buf.String()
+	if !strings.Contains(got, "2002 Geogaddi [IDM, Ambient / Warp WARPCD109]") {
+		t.Fatalf("expected genre/label suffix in output, got %q", got)
+	}
+	if !strings.Contains(got, "01 Ready Lets Go (1:35)") {
+		t.Fatalf("expected track with duration in output, got %q", got)
+	}
+}
+
+func TestPrintAlbumsTSV(t *testing.T) {
+	var buf bytes.Buffer
+	printAlbumsTSV(&buf, []*Album{{
+		Title:         "Geogaddi",
+		Year:          2002,
+		Genres:        []string{"IDM", "Ambient"},
+		Label:         "Warp",
+		CatalogNumber: "WARPCD109",
+		CoverArtURL:   "https://coverartarchive.org/release-group/x/front",
+	}})
+
+	want := "2002\tGeogaddi\tIDM,Ambient\tWarp\tWARPCD109\thttps://coverartarchive.org/release-group/x/front\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected tsv output:\n got:  %q\n want: %q", got, want)
+	}
+}
+
+func TestAlbumDetailSuffix(t *testing.T) {
+	if got := albumDetailSuffix(&Album{}); got != "" {
+		t.Fatalf("expected no suffix for a bare album, got %q", got)
+	}
+
+	got := albumDetailSuffix(&Album{Genres: []string{"Soundtrack"}})
+	if got != " [Soundtrack]" {
+		t.Fatalf("expected genre-only suffix, got %q", got)
+	}
+
+	got = albumDetailSuffix(&Album{Label: "Warp"})
+	if got != " [Warp]" {
+		t.Fatalf("expected label-only suffix, got %q", got)
+	}
+
+	got = albumDetailSuffix(&Album{Label: "Warp", CatalogNumber: "WARPCD109"})
+	if got != " [Warp WARPCD109]" {
+		t.Fatalf("expected label+catalog suffix, got %q", got)
+	}
+}
+
+func TestTrackDurationSuffix(t *testing.T) {
+	if got := trackDurationSuffix(&Track{}); got != "" {
+		t.Fatalf("expected no suffix for a track with no duration, got %q", got)
+	}
+
+	if got := trackDurationSuffix(&Track{DurationSeconds: 95}); got != " (1:35)" {
+		t.Fatalf("expected formatted duration, got %q", got)
+	}
+}
+
+func TestPrintAlbumsUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printAlbums(&buf, &Artist{}, nil, "xml"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MusicBrainzSource looks up artists and release groups via the
+// MusicBrainz web service (https://musicbrainz.org/doc/MusicBrainz_API).
+type MusicBrainzSource struct{}
+
+func (s *MusicBrainzSource) Name() string {
+	return "musicbrainz"
+}
+
+type mbArtistResult struct {
+	Artists []*mbArtist `json:"artists"`
+}
+
+type mbArtist struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type mbReleaseGroupResult struct {
+	ReleaseGroups []*mbReleaseGroup `json:"release-groups"`
+	Count         int               `json:"release-group-count"`
+	Offset        int               `json:"release-group-offset"`
+}
+
+type mbReleaseGroup struct {
+	Id               string   `json:"id"`
+	Title            string   `json:"title"`
+	PrimaryType      string   `json:"primary-type"`
+	SecondaryTypes   []string `json:"secondary-types"`
+	FirstReleaseDate string   `json:"first-release-date"`
+}
+
+func (s *MusicBrainzSource) FindArtist(query string) (*Artist, error) {
+	params := url.Values{}
+	params.Add("query", fmt.Sprintf("artist:%s", query))
+	params.Add("limit", "1")
+	params.Add("fmt", "json")
+
+	req := &http.Request{
+		Method: "GET",
+		Host:   "musicbrainz.org",
+		URL: &url.URL{
+			Host:     "musicbrainz.org",
+			Scheme:   "http",
+			Path:     "/ws/2/artist/",
+			RawQuery: params.Encode(),
+		},
+		Header: http.Header{
+			"User-Agent": {UserAgent},
+		},
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	result := &mbArtistResult{}
+	err = json.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Artists) == 0 {
+		return nil, fmt.Errorf("No artists found")
+	}
+
+	artist := result.Artists[0]
+	return &Artist{Id: artist.Id, Name: artist.Name}, nil
+}
+
+func (s *MusicBrainzSource) FindAlbums(artistID string) ([]*Album, error) {
+	releaseGroups, err := s.findReleaseGroups(artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	albums := make([]*Album, 0, 0)
+
+	for _, rg := range releaseGroups {
+		albums = append(albums, &Album{
+			Id:             rg.Id,
+			Title:          rg.Title,
+			Year:           formatYear(rg.FirstReleaseDate),
+			Type:           strings.ToLower(rg.PrimaryType),
+			SecondaryTypes: lowerAll(rg.SecondaryTypes),
+			sourceName:     s.Name(),
+		})
+	}
+
+	// Sort albums by year
+	sort.Sort(AlbumByYear(albums))
+
+	return albums, nil
+}
+
+func lowerAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToLower(v)
+	}
+	return out
+}
+
+// mbReleaseGroupPageSize is the page size used when paginating
+// release-groups; it's also the MusicBrainz API's own per-request cap.
+const mbReleaseGroupPageSize = 100
+
+// findReleaseGroups fetches every release-group for an artist, regardless
+// of type, paginating past the API's 100-per-request limit so prolific
+// artists don't lose albums to truncation. Filtering by primary/secondary
+// type happens centrally, see AlbumFilter.
+func (s *MusicBrainzSource) findReleaseGroups(artistID string) ([]*mbReleaseGroup, error) {
+	var all []*mbReleaseGroup
+
+	for offset := 0; ; offset += mbReleaseGroupPageSize {
+		result, err := s.findReleaseGroupsPage(artistID, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, result.ReleaseGroups...)
+
+		if len(all) >= result.Count || len(result.ReleaseGroups) == 0 {
+			break
+		}
+	}
+
+	return all, nil
+}
+
+func (s *MusicBrainzSource) findReleaseGroupsPage(artistID string, offset int) (*mbReleaseGroupResult, error) {
+	params := url.Values{}
+	params.Add("artist", artistID)
+	params.Add("limit", strconv.Itoa(mbReleaseGroupPageSize))
+	params.Add("offset", strconv.Itoa(offset))
+	params.Add("fmt", "json")
+
+	req := &http.Request{
+		Method: "GET",
+		Host:   "musicbrainz.org",
+		URL: &url.URL{
+			Host:     "musicbrainz.org",
+			Scheme:   "http",
+			Path:     "/ws/2/release-group/",
+			RawQuery: params.Encode(),
+		},
+		Header: http.Header{
+			"User-Agent": {UserAgent},
+		},
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	result := &mbReleaseGroupResult{}
+	err = json.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+type mbReleaseGroupDetail struct {
+	Genres   []*mbGenre   `json:"genres"`
+	Releases []*mbRelease `json:"releases"`
+}
+
+type mbGenre struct {
+	Name string `json:"name"`
+}
+
+type mbRelease struct {
+	Id string `json:"id"`
+}
+
+type mbReleaseDetail struct {
+	LabelInfo []*mbLabelInfo `json:"label-info"`
+	Media     []*mbMedium    `json:"media"`
+}
+
+type mbLabelInfo struct {
+	CatalogNumber string   `json:"catalog-number"`
+	Label         *mbLabel `json:"label"`
+}
+
+type mbLabel struct {
+	Name string `json:"name"`
+}
+
+type mbMedium struct {
+	Tracks []*mbTrack `json:"tracks"`
+}
+
+type mbTrack struct {
+	Position  int          `json:"position"`
+	Title     string       `json:"title"`
+	Length    int          `json:"length"`
+	Recording *mbRecording `json:"recording"`
+}
+
+type mbRecording struct {
+	Length int `json:"length"`
+}
+
+// FindAlbumDetail fetches genres and a representative release's label,
+// catalog number and track listing, and builds a coverartarchive.org URL.
+func (s *MusicBrainzSource) FindAlbumDetail(album *Album) error {
+	if album.Id == "" {
+		return nil
+	}
+
+	rg, err := s.fetchReleaseGroupDetail(album.Id)
+	if err != nil {
+		return err
+	}
+
+	for _, genre := range rg.Genres {
+		album.Genres = append(album.Genres, genre.Name)
+	}
+	if len(album.Genres) > 0 {
+		album.Genre = album.Genres[0]
+	}
+
+	album.CoverArtURL = fmt.Sprintf("https://coverartarchive.org/release-group/%s/front", album.Id)
+
+	if len(rg.Releases) == 0 {
+		return nil
+	}
+
+	release, err := s.fetchReleaseDetail(rg.Releases[0].Id)
+	if err != nil {
+		return err
+	}
+
+	if len(release.LabelInfo) > 0 {
+		info := release.LabelInfo[0]
+		album.CatalogNumber = info.CatalogNumber
+		if info.Label != nil {
+			album.Label = info.Label.Name
+		}
+	}
+
+	for _, medium := range release.Media {
+		for _, track := range medium.Tracks {
+			length := track.Length
+			if length == 0 && track.Recording != nil {
+				length = track.Recording.Length
+			}
+
+			album.Tracks = append(album.Tracks, &Track{
+				Position:        track.Position,
+				Title:           track.Title,
+				DurationSeconds: length / 1000,
+			})
+		}
+	}
+
+	return nil
+}
+
+func (s *MusicBrainzSource) fetchReleaseGroupDetail(id string) (*mbReleaseGroupDetail, error) {
+	params := url.Values{}
+	params.Add("inc", "genres+releases")
+	params.Add("fmt", "json")
+
+	req := &http.Request{
+		Method: "GET",
+		Host:   "musicbrainz.org",
+		URL: &url.URL{
+			Host:     "musicbrainz.org",
+			Scheme:   "http",
+			Path:     fmt.Sprintf("/ws/2/release-group/%s", id),
+			RawQuery: params.Encode(),
+		},
+		Header: http.Header{
+			"User-Agent": {UserAgent},
+		},
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	result := &mbReleaseGroupDetail{}
+	err = json.NewDecoder(res.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *MusicBrainzSource) fetchReleaseDetail(id string) (*mbReleaseDetail, error) {
+	params := url.Values{}
+	params.Add("inc", "labels+recordings")
+	params.Add("fmt", "json")
+
+	req := &http.Request{
+		Method: "GET",
+		Host:   "musicbrainz.org",
+		URL: &url.URL{
+			Host:     "musicbrainz.org",
+			Scheme:   "http",
+			Path:     fmt.Sprintf("/ws/2/release/%s", id),
+			RawQuery: params.Encode(),
+		},
+		Header: http.Header{
+			"User-Agent": {UserAgent},
+		},
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	result := &mbReleaseDetail{}
+	err = json.NewDecoder(res.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func formatYear(date string) int {
+	re := regexp.MustCompile("^([0-9]{4})")
+	matches := re.FindStringSubmatch(date)
+	if len(matches) != 2 {
+		return 0
+	}
+
+	year, _ := strconv.Atoi(matches[1])
+	return year
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// RelatedArtistSource finds artists related to a given artist, for the
+// `albums similar` subcommand.
+type RelatedArtistSource interface {
+	Name() string
+	FindRelatedArtists(artist *Artist) ([]*Artist, error)
+}
+
+// runSimilar implements `albums similar <artist>`.
+func runSimilar(args []string) {
+	fs := flag.NewFlagSet("similar", flag.ExitOnError)
+	depth := fs.Int("depth", 1, "Transitively expand related artists up to this many levels, de-duplicating")
+	noCache, cacheTTL := addCacheFlags(fs)
+	fs.Parse(args)
+
+	*depth = clampDepth(*depth)
+
+	http.DefaultClient.Transport = NewCachingClient(!*noCache, *cacheTTL)
+
+	if fs.NArg() < 1 {
+		fmt.Fprintf(os.Stderr, "Usage: albums similar <artist>\n")
+		os.Exit(1)
+	}
+
+	query := strings.Join(fs.Args(), " ")
+
+	mb := &MusicBrainzSource{}
+	artist, err := mb.FindArtist(query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	source := relatedArtistSource()
+
+	related, err := source.FindRelatedArtists(artist)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	related = expandRelated(source, artist, related, *depth)
+
+	if len(related) == 0 {
+		fmt.Printf("No artists found related to %s\n", artist.Name)
+		return
+	}
+
+	fmt.Printf("Artists related to %s\n", artist.Name)
+	for i, a := range related {
+		fmt.Printf("%d. %s\n", i+1, a.Name)
+	}
+}
+
+// relatedArtistSource picks Spotify when a token is configured, otherwise
+// falls back to MusicBrainz artist relationships.
+func relatedArtistSource() RelatedArtistSource {
+	if os.Getenv("SPOTIFY_TOKEN") != "" {
+		return &SpotifyRelatedSource{}
+	}
+	return &MusicBrainzRelatedSource{}
+}
+
+// clampDepth floors --depth at 1 (just the directly related artists);
+// values below that would otherwise be silently treated as 1 anyway.
+func clampDepth(depth int) int {
+	if depth < 1 {
+		return 1
+	}
+	return depth
+}
+
+// expandRelated does a breadth-first expansion of related artists up to
+// depth levels, de-duplicating by artist ID and excluding the root artist.
+func expandRelated(source RelatedArtistSource, root *Artist, firstLevel []*Artist, depth int) []*Artist {
+	seen := map[string]bool{root.Id: true}
+	ranked := make([]*Artist, 0, len(firstLevel))
+
+	frontier := make([]*Artist, 0, len(firstLevel))
+	for _, a := range firstLevel {
+		if seen[a.Id] {
+			continue
+		}
+		seen[a.Id] = true
+		ranked = append(ranked, a)
+		frontier = append(frontier, a)
+	}
+
+	for level := 1; level < depth; level++ {
+		var next []*Artist
+
+		for _, a := range frontier {
+			related, err := source.FindRelatedArtists(a)
+			if err != nil {
+				continue
+			}
+
+			for _, r := range related {
+				if seen[r.Id] {
+					continue
+				}
+				seen[r.Id] = true
+				ranked = append(ranked, r)
+				next = append(next, r)
+			}
+		}
+
+		if len(next) == 0 {
+			break
+		}
+		frontier = next
+	}
+
+	return ranked
+}
+
+// MusicBrainzRelatedSource reads artist-rels (member of band, collaboration,
+// etc.) from the MusicBrainz artist endpoint.
+type MusicBrainzRelatedSource struct{}
+
+func (s *MusicBrainzRelatedSource) Name() string {
+	return "musicbrainz"
+}
+
+type mbArtistRelResult struct {
+	Relations []*mbArtistRel `json:"relations"`
+}
+
+type mbArtistRel struct {
+	TargetType string    `json:"target-type"`
+	Artist     *mbArtist `json:"artist"`
+}
+
+func (s *MusicBrainzRelatedSource) FindRelatedArtists(artist *Artist) ([]*Artist, error) {
+	params := url.Values{}
+	params.Add("inc", "artist-rels")
+	params.Add("fmt", "json")
+
+	req := &http.Request{
+		Method: "GET",
+		Host:   "musicbrainz.org",
+		URL: &url.URL{
+			Host:     "musicbrainz.org",
+			Scheme:   "http",
+			Path:     fmt.Sprintf("/ws/2/artist/%s", artist.Id),
+			RawQuery: params.Encode(),
+		},
+		Header: http.Header{
+			"User-Agent": {UserAgent},
+		},
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	result := &mbArtistRelResult{}
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		return nil, err
+	}
+
+	related := make([]*Artist, 0, len(result.Relations))
+	for _, rel := range result.Relations {
+		if rel.TargetType != "artist" || rel.Artist == nil {
+			continue
+		}
+		related = append(related, &Artist{Id: rel.Artist.Id, Name: rel.Artist.Name})
+	}
+
+	return related, nil
+}
+
+// SpotifyRelatedSource uses Spotify's related-artists endpoint, which
+// requires SPOTIFY_TOKEN to be set to a valid Bearer token.
+type SpotifyRelatedSource struct{}
+
+func (s *SpotifyRelatedSource) Name() string {
+	return "spotify"
+}
+
+type spotifySearchResult struct {
+	Artists *spotifyArtistPage `json:"artists"`
+}
+
+type spotifyArtistPage struct {
+	Items []*spotifyArtist `json:"items"`
+}
+
+type spotifyArtist struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type spotifyRelatedResult struct {
+	Artists []*spotifyArtist `json:"artists"`
+}
+
+func (s *SpotifyRelatedSource) FindRelatedArtists(artist *Artist) ([]*Artist, error) {
+	spotifyID, err := s.findSpotifyID(artist.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &spotifyRelatedResult{}
+	err = s.get(fmt.Sprintf("/v1/artists/%s/related-artists", spotifyID), url.Values{}, result)
+	if err != nil {
+		return nil, err
+	}
+
+	related := make([]*Artist, 0, len(result.Artists))
+	for _, a := range result.Artists {
+		related = append(related, &Artist{Id: a.Id, Name: a.Name})
+	}
+
+	return related, nil
+}
+
+func (s *SpotifyRelatedSource) findSpotifyID(name string) (string, error) {
+	params := url.Values{}
+	params.Add("q", name)
+	params.Add("type", "artist")
+	params.Add("limit", "1")
+
+	result := &spotifySearchResult{}
+	err := s.get("/v1/search", params, result)
+	if err != nil {
+		return "", err
+	}
+
+	if result.Artists == nil || len(result.Artists.Items) == 0 {
+		return "", fmt.Errorf("No matching artist found on Spotify")
+	}
+
+	return result.Artists.Items[0].Id, nil
+}
+
+func (s *SpotifyRelatedSource) get(path string, params url.Values, out interface{}) error {
+	req := &http.Request{
+		Method: "GET",
+		Host:   "api.spotify.com",
+		URL: &url.URL{
+			Host:     "api.spotify.com",
+			Scheme:   "https",
+			Path:     path,
+			RawQuery: params.Encode(),
+		},
+		Header: http.Header{
+			"User-Agent":    {UserAgent},
+			"Authorization": {"Bearer " + os.Getenv("SPOTIFY_TOKEN")},
+		},
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("Spotify request failed: %s", res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
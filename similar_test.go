@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+)
+
+// fakeRelatedSource returns a canned related-artists list per artist ID, so
+// expandRelated's BFS can be tested without hitting the network.
+type fakeRelatedSource struct {
+	related map[string][]*Artist
+}
+
+func (s *fakeRelatedSource) Name() string {
+	return "fake"
+}
+
+func (s *fakeRelatedSource) FindRelatedArtists(artist *Artist) ([]*Artist, error) {
+	return s.related[artist.Id], nil
+}
+
+func TestExpandRelatedDepthOneReturnsFirstLevelOnly(t *testing.T) {
+	root := &Artist{Id: "root", Name: "Root"}
+	firstLevel := []*Artist{{Id: "a", Name: "A"}, {Id: "b", Name: "B"}}
+	source := &fakeRelatedSource{
+		related: map[string][]*Artist{
+			"a": {{Id: "c", Name: "C"}},
+		},
+	}
+
+	got := expandRelated(source, root, firstLevel, 1)
+
+	if len(got) != 2 || got[0].Id != "a" || got[1].Id != "b" {
+		t.Fatalf("expected only first-level artists at depth 1, got %+v", got)
+	}
+}
+
+func TestExpandRelatedExpandsAndDedupesAcrossLevels(t *testing.T) {
+	root := &Artist{Id: "root", Name: "Root"}
+	firstLevel := []*Artist{{Id: "a", Name: "A"}, {Id: "b", Name: "B"}}
+	source := &fakeRelatedSource{
+		related: map[string][]*Artist{
+			// "a" relates to the root (already seen) and a new artist "c".
+			"a": {{Id: "root", Name: "Root"}, {Id: "c", Name: "C"}},
+			// "b" relates to "c" too, which should not be added twice.
+			"b": {{Id: "c", Name: "C"}},
+			"c": {{Id: "a", Name: "A"}},
+		},
+	}
+
+	got := expandRelated(source, root, firstLevel, 3)
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 deduped artists, got %+v", got)
+	}
+	ids := map[string]bool{}
+	for _, a := range got {
+		ids[a.Id] = true
+	}
+	if !ids["a"] || !ids["b"] || !ids["c"] {
+		t.Fatalf("expected a, b and c in expanded result, got %+v", got)
+	}
+}
+
+func TestExpandRelatedStopsWhenFrontierExhausted(t *testing.T) {
+	root := &Artist{Id: "root", Name: "Root"}
+	firstLevel := []*Artist{{Id: "a", Name: "A"}}
+	source := &fakeRelatedSource{related: map[string][]*Artist{}}
+
+	got := expandRelated(source, root, firstLevel, 5)
+
+	if len(got) != 1 || got[0].Id != "a" {
+		t.Fatalf("expected expansion to stop once the frontier is empty, got %+v", got)
+	}
+}
+
+// notFoundTransport simulates Spotify's retired related-artists endpoint
+// returning a 404 with an error body instead of a related-artists list.
+type notFoundTransport struct{}
+
+func (t *notFoundTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body := []byte(`{"error": {"status": 404, "message": "Not Found"}}`)
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func TestSpotifyRelatedSourceGetSurfacesNon200(t *testing.T) {
+	restore := swapDefaultTransport(&notFoundTransport{})
+	defer restore()
+
+	s := &SpotifyRelatedSource{}
+	var out struct{}
+	err := s.get("/v1/artists/x/related-artists", nil, &out)
+	if err == nil {
+		t.Fatal("expected an error for a non-200 response instead of a silently empty result")
+	}
+}
+
+func TestClampDepth(t *testing.T) {
+	cases := map[int]int{0: 1, -3: 1, 1: 1, 2: 2}
+	for in, want := range cases {
+		if got := clampDepth(in); got != want {
+			t.Fatalf("clampDepth(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MetadataSource resolves artists and their discography from a single
+// backend (MusicBrainz, Discogs, VGMdb, ...).
+type MetadataSource interface {
+	// Name is the identifier used with the --source flag.
+	Name() string
+	FindArtist(query string) (*Artist, error)
+	FindAlbums(artistID string) ([]*Album, error)
+
+	// FindAlbumDetail fills in album's detail fields (Genres, Label,
+	// CatalogNumber, CoverArtURL, Tracks) in place. Sources that don't
+	// have this information just leave album untouched.
+	FindAlbumDetail(album *Album) error
+}
+
+// sources lists the available MetadataSources in priority order. When no
+// --source is given, they are tried in this order and the results are
+// merged.
+var sources = []MetadataSource{
+	&MusicBrainzSource{},
+	&DiscogsSource{},
+	&VGMdbSource{},
+}
+
+func sourceByName(name string) (MetadataSource, error) {
+	for _, source := range sources {
+		if source.Name() == name {
+			return source, nil
+		}
+	}
+	return nil, fmt.Errorf("Unknown source: %s", name)
+}
+
+// lookupAlbums resolves the artist and their discography using a single
+// named source.
+func lookupAlbums(source MetadataSource, query string) (*Artist, []*Album, error) {
+	artist, err := source.FindArtist(query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	albums, err := source.FindAlbums(artist.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return artist, albums, nil
+}
+
+// lookupAlbumsMerged tries every source in priority order, merging their
+// albums by title+year and dropping duplicates. The artist returned is the
+// one from the first source that found a match.
+func lookupAlbumsMerged(query string) (*Artist, []*Album, error) {
+	return mergeAlbumsFromSources(sources, query)
+}
+
+// mergeAlbumsFromSources implements lookupAlbumsMerged against an explicit
+// list of sources, so the merge/dedup logic can be tested without hitting
+// the network.
+func mergeAlbumsFromSources(srcs []MetadataSource, query string) (*Artist, []*Album, error) {
+	var artist *Artist
+	var lastErr error
+	merged := make([]*Album, 0)
+	seen := make(map[string]bool)
+
+	for _, source := range srcs {
+		sourceArtist, albums, err := lookupAlbums(source, query)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if artist == nil {
+			artist = sourceArtist
+		} else if !sameArtist(artist, sourceArtist) {
+			// This source resolved the query to a different artist than
+			// the primary source (e.g. Discogs' first match for "Nirvana"
+			// is the 60s UK band, MusicBrainz's is the grunge one). Merging
+			// its albums in would contaminate the primary artist's
+			// discography, so skip it.
+			continue
+		}
+
+		for _, album := range albums {
+			key := albumKey(album)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, album)
+		}
+	}
+
+	if artist == nil {
+		return nil, nil, lastErr
+	}
+
+	sort.Sort(AlbumByYear(merged))
+
+	return artist, merged, nil
+}
+
+// sameArtist reports whether two sources' artists are plausibly the same
+// real-world artist, comparing names the same way album titles are
+// compared (case/whitespace insensitive).
+func sameArtist(a, b *Artist) bool {
+	return normalizeTitle(a.Name) == normalizeTitle(b.Name)
+}
+
+func albumKey(album *Album) string {
+	return fmt.Sprintf("%s\x00%d", normalizeTitle(album.Title), album.Year)
+}
+
+// fetchAlbumDetails fills in the detail fields of every album, asking
+// whichever MetadataSource originally produced it.
+func fetchAlbumDetails(albums []*Album) error {
+	for _, album := range albums {
+		source, err := sourceByName(album.sourceName)
+		if err != nil {
+			continue
+		}
+
+		if err := source.FindAlbumDetail(album); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
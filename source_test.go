@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// fakeSource is a minimal in-memory MetadataSource for testing the merge
+// and dedup logic without touching the network.
+type fakeSource struct {
+	name   string
+	artist *Artist
+	albums []*Album
+	err    error
+}
+
+func (s *fakeSource) Name() string { return s.name }
+
+func (s *fakeSource) FindArtist(query string) (*Artist, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.artist, nil
+}
+
+func (s *fakeSource) FindAlbums(artistID string) ([]*Album, error) {
+	return s.albums, nil
+}
+
+func (s *fakeSource) FindAlbumDetail(album *Album) error { return nil }
+
+func TestAlbumKey(t *testing.T) {
+	a := &Album{Title: "Abbey Road", Year: 1969}
+	b := &Album{Title: "  abbey road ", Year: 1969}
+	c := &Album{Title: "Abbey Road", Year: 1970}
+
+	if albumKey(a) != albumKey(b) {
+		t.Fatalf("expected matching keys for titles differing only by case/whitespace: %q vs %q", albumKey(a), albumKey(b))
+	}
+	if albumKey(a) == albumKey(c) {
+		t.Fatal("expected different keys for albums released in different years")
+	}
+}
+
+func TestMergeAlbumsFromSourcesDedupesByTitleAndYear(t *testing.T) {
+	mb := &fakeSource{
+		name:   "musicbrainz",
+		artist: &Artist{Id: "mb-1", Name: "Boards of Canada"},
+		albums: []*Album{
+			{Title: "Music Has the Right to Children", Year: 1998},
+			{Title: "Geogaddi", Year: 2002},
+		},
+	}
+	discogs := &fakeSource{
+		name:   "discogs",
+		artist: &Artist{Id: "dg-1", Name: "Boards of Canada"},
+		albums: []*Album{
+			{Title: "music has the right to children", Year: 1998}, // duplicate of mb's
+			{Title: "Tomorrow's Harvest", Year: 2013},
+		},
+	}
+
+	artist, merged, err := mergeAlbumsFromSources([]MetadataSource{mb, discogs}, "Boards of Canada")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if artist.Id != "mb-1" {
+		t.Fatalf("expected the first source's artist to win, got %+v", artist)
+	}
+
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 deduped albums, got %d: %+v", len(merged), merged)
+	}
+
+	if merged[0].Year != 1998 || merged[len(merged)-1].Year != 2013 {
+		t.Fatalf("expected merged albums sorted by year, got %+v", merged)
+	}
+}
+
+func TestMergeAlbumsFromSourcesFallsBackWhenASourceErrors(t *testing.T) {
+	broken := &fakeSource{name: "musicbrainz", err: fmt.Errorf("no artists found")}
+	ok := &fakeSource{
+		name:   "discogs",
+		artist: &Artist{Id: "dg-1", Name: "Boards of Canada"},
+		albums: []*Album{{Title: "Geogaddi", Year: 2002}},
+	}
+
+	artist, merged, err := mergeAlbumsFromSources([]MetadataSource{broken, ok}, "Boards of Canada")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if artist.Id != "dg-1" {
+		t.Fatalf("expected the artist from the working source, got %+v", artist)
+	}
+	if len(merged) != 1 {
+		t.Fatalf("expected a single album from the working source, got %+v", merged)
+	}
+}
+
+func TestMergeAlbumsFromSourcesSkipsMismatchedArtist(t *testing.T) {
+	mb := &fakeSource{
+		name:   "musicbrainz",
+		artist: &Artist{Id: "mb-1", Name: "Nirvana"},
+		albums: []*Album{
+			{Title: "Nevermind", Year: 1991},
+		},
+	}
+	discogs := &fakeSource{
+		name:   "discogs",
+		artist: &Artist{Id: "dg-1", Name: "The Nirvana"}, // resolved to the 60s UK band, not a match
+		albums: []*Album{
+			{Title: "The Story of Simon Simopath", Year: 1967},
+		},
+	}
+
+	artist, merged, err := mergeAlbumsFromSources([]MetadataSource{mb, discogs}, "Nirvana")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if artist.Id != "mb-1" {
+		t.Fatalf("expected the primary source's artist to win, got %+v", artist)
+	}
+	if len(merged) != 1 || merged[0].Title != "Nevermind" {
+		t.Fatalf("expected the mismatched source's albums to be dropped, got %+v", merged)
+	}
+}
+
+func TestMergeAlbumsFromSourcesReturnsErrorWhenAllSourcesFail(t *testing.T) {
+	broken := &fakeSource{name: "musicbrainz", err: fmt.Errorf("no artists found")}
+
+	_, _, err := mergeAlbumsFromSources([]MetadataSource{broken}, "Unknown Artist")
+	if err == nil {
+		t.Fatal("expected an error when every source fails")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+// Artist is the source-agnostic representation of an artist, regardless of
+// which MetadataSource resolved it.
+type Artist struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Album is the source-agnostic representation of a release grouping,
+// regardless of which MetadataSource it came from. The detail fields
+// (Genres, Label, CatalogNumber, CoverArtURL, Tracks) are only populated
+// when --detail is passed, since they cost an extra request per album.
+type Album struct {
+	Id    string `json:"id,omitempty"`
+	Title string `json:"title"`
+	Year  int    `json:"year"`
+
+	// Type and SecondaryTypes mirror MusicBrainz's primary/secondary
+	// release-group types (album, ep, single, live, compilation,
+	// soundtrack, remix, ...) and drive --include/--exclude filtering.
+	Type           string   `json:"type,omitempty"`
+	SecondaryTypes []string `json:"secondary_types,omitempty"`
+
+	Genre         string   `json:"genre,omitempty"`
+	Genres        []string `json:"genres,omitempty"`
+	Label         string   `json:"label,omitempty"`
+	CatalogNumber string   `json:"catalog_number,omitempty"`
+	CoverArtURL   string   `json:"cover_art_url,omitempty"`
+	Tracks        []*Track `json:"tracks,omitempty"`
+
+	// sourceName records which MetadataSource produced this album, so a
+	// later --detail pass knows who to ask for per-release detail.
+	sourceName string
+}
+
+// Track is a single entry in an Album's track listing.
+type Track struct {
+	Position        int    `json:"position"`
+	Title           string `json:"title"`
+	DurationSeconds int    `json:"duration_seconds,omitempty"`
+}
+
+type AlbumByYear []*Album
+
+func (a AlbumByYear) Len() int           { return len(a) }
+func (a AlbumByYear) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a AlbumByYear) Less(i, j int) bool { return a[i].Year < a[j].Year }
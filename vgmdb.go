@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// VGMdbSource looks up artists (composers) and albums via VGMdb's unofficial
+// JSON API (https://vgmdb.info/search), which covers video game and anime
+// soundtracks that MusicBrainz and Discogs rarely catalog.
+type VGMdbSource struct{}
+
+func (s *VGMdbSource) Name() string {
+	return "vgmdb"
+}
+
+type vgmdbSearchResult struct {
+	Artists []*vgmdbArtistRef `json:"artists"`
+}
+
+type vgmdbArtistRef struct {
+	Link string `json:"link"`
+	Name string `json:"name"`
+}
+
+type vgmdbArtist struct {
+	Name   string        `json:"name"`
+	Albums []*vgmdbAlbum `json:"discography"`
+}
+
+type vgmdbAlbum struct {
+	Link  string `json:"link"`
+	Title string `json:"title"`
+	Date  string `json:"release_date"`
+}
+
+type vgmdbAlbumDetail struct {
+	Catalog        string       `json:"catalog"`
+	Publisher      string       `json:"publisher"`
+	Classification string       `json:"classification"`
+	PictureFull    string       `json:"picture_full"`
+	Discs          []*vgmdbDisc `json:"discs"`
+}
+
+type vgmdbDisc struct {
+	Tracks []*vgmdbTrack `json:"tracks"`
+}
+
+type vgmdbTrack struct {
+	Names []*vgmdbTrackName `json:"names"`
+}
+
+type vgmdbTrackName struct {
+	Lang string `json:"lang"`
+	Name string `json:"name"`
+}
+
+func (s *VGMdbSource) FindArtist(query string) (*Artist, error) {
+	result := &vgmdbSearchResult{}
+	err := s.get(fmt.Sprintf("/search/artists/%s", url.PathEscape(query)), result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Artists) == 0 {
+		return nil, fmt.Errorf("No artists found")
+	}
+
+	artist := result.Artists[0]
+	return &Artist{Id: artist.Link, Name: artist.Name}, nil
+}
+
+func (s *VGMdbSource) FindAlbums(artistID string) ([]*Album, error) {
+	artist := &vgmdbArtist{}
+	err := s.get("/"+artistID, artist)
+	if err != nil {
+		return nil, err
+	}
+
+	albums := make([]*Album, 0, 0)
+
+	for _, album := range artist.Albums {
+		albums = append(albums, &Album{
+			Id:    album.Link,
+			Title: album.Title,
+			Year:  formatYear(album.Date),
+			// VGMdb's discography listing doesn't categorize by MB-style
+			// secondary types either; soundtracks/arranges are exposed via
+			// --detail's Genre instead.
+			Type:       "album",
+			sourceName: s.Name(),
+		})
+	}
+
+	return albums, nil
+}
+
+// FindAlbumDetail fetches an album's catalog number, publisher (used as
+// Label), cover art and track titles.
+func (s *VGMdbSource) FindAlbumDetail(album *Album) error {
+	if album.Id == "" {
+		return nil
+	}
+
+	detail := &vgmdbAlbumDetail{}
+	err := s.get("/"+album.Id, detail)
+	if err != nil {
+		return err
+	}
+
+	if detail.Classification != "" {
+		album.Genre = detail.Classification
+		album.Genres = []string{detail.Classification}
+	}
+
+	album.Label = detail.Publisher
+	album.CatalogNumber = detail.Catalog
+	album.CoverArtURL = detail.PictureFull
+
+	position := 1
+	for _, disc := range detail.Discs {
+		for _, track := range disc.Tracks {
+			album.Tracks = append(album.Tracks, &Track{
+				Position: position,
+				Title:    vgmdbTrackTitle(track),
+			})
+			position++
+		}
+	}
+
+	return nil
+}
+
+// vgmdbTrackTitle prefers the English track name when VGMdb lists several,
+// falling back to the first name given.
+func vgmdbTrackTitle(track *vgmdbTrack) string {
+	if len(track.Names) == 0 {
+		return ""
+	}
+
+	for _, name := range track.Names {
+		if strings.EqualFold(name.Lang, "English") {
+			return name.Name
+		}
+	}
+
+	return track.Names[0].Name
+}
+
+func (s *VGMdbSource) get(path string, out interface{}) error {
+	req := &http.Request{
+		Method: "GET",
+		Host:   "vgmdb.info",
+		URL: &url.URL{
+			Host:     "vgmdb.info",
+			Scheme:   "https",
+			Path:     path,
+			RawQuery: "format=json",
+		},
+		Header: http.Header{
+			"User-Agent": {UserAgent},
+		},
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("VGMdb request failed: %s", res.Status)
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
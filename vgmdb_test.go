@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestVgmdbTrackTitlePrefersEnglish(t *testing.T) {
+	track := &vgmdbTrack{
+		Names: []*vgmdbTrackName{
+			{Lang: "Japanese", Name: "曲名"},
+			{Lang: "English", Name: "Song Title"},
+		},
+	}
+
+	if got := vgmdbTrackTitle(track); got != "Song Title" {
+		t.Fatalf("expected the English name to be preferred, got %q", got)
+	}
+}
+
+func TestVgmdbTrackTitleFallsBackToFirstName(t *testing.T) {
+	track := &vgmdbTrack{
+		Names: []*vgmdbTrackName{
+			{Lang: "Japanese", Name: "曲名"},
+			{Lang: "Japanese (romaji)", Name: "Kyokumei"},
+		},
+	}
+
+	if got := vgmdbTrackTitle(track); got != "曲名" {
+		t.Fatalf("expected the first name when no English name is present, got %q", got)
+	}
+}
+
+func TestVgmdbTrackTitleEmpty(t *testing.T) {
+	if got := vgmdbTrackTitle(&vgmdbTrack{}); got != "" {
+		t.Fatalf("expected an empty title for a track with no names, got %q", got)
+	}
+}